@@ -116,6 +116,21 @@ type ReplicationSourceRsyncSpec struct {
 	// sshUser is the username for outgoing SSH connections. Defaults to "root".
 	//+optional
 	SSHUser *string `json:"sshUser,omitempty"`
+	// moverResources can be used to set the resource requests and limits of
+	// the data mover containers.
+	//+optional
+	MoverResources *corev1.ResourceRequirements `json:"moverResources,omitempty"`
+	// nodeSelector is a map of node labels the data mover Pods must match to
+	// be scheduled on a node.
+	//+optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// tolerations allow the data mover Pods to be scheduled onto nodes with
+	// matching taints.
+	//+optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// affinity sets the scheduling constraints of the data mover Pods.
+	//+optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
 }
 
 // ReplicationSourceRcloneSpec defines the field for rclone in replicationSource.
@@ -372,6 +372,56 @@ var _ = Describe("ReplicationDestination", func() {
 				Expect(*job.Spec.Parallelism).To(Equal(parallelism))
 			})
 		})
+		Context("when moverResources are specified", func() {
+			BeforeEach(func() {
+				rd.Spec.Rsync.MoverResources = &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("250m"),
+					},
+				}
+			})
+			It("they are used by the sync Job", func() {
+				job := &batchv1.Job{}
+				Eventually(func() error {
+					return k8sClient.Get(ctx, types.NamespacedName{Name: "volsync-rsync-dest-" + rd.Name, Namespace: rd.Namespace}, job)
+				}, maxWait, interval).Should(Succeed())
+				resources := job.Spec.Template.Spec.Containers[0].Resources
+				Expect(resources.Requests.Cpu().Equal(resource.MustParse("250m"))).To(BeTrue())
+			})
+		})
+		Context("when scheduling constraints are specified", func() {
+			BeforeEach(func() {
+				rd.Spec.Rsync.NodeSelector = map[string]string{"storage": "fast"}
+				rd.Spec.Rsync.Tolerations = []corev1.Toleration{{
+					Key:      "dedicated",
+					Operator: corev1.TolerationOpExists,
+					Effect:   corev1.TaintEffectNoSchedule,
+				}}
+				rd.Spec.Rsync.Affinity = &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+								MatchExpressions: []corev1.NodeSelectorRequirement{{
+									Key:      "topology.kubernetes.io/zone",
+									Operator: corev1.NodeSelectorOpIn,
+									Values:   []string{"zone-a"},
+								}},
+							}},
+						},
+					},
+				}
+			})
+			It("they are used by the sync Job", func() {
+				job := &batchv1.Job{}
+				Eventually(func() error {
+					return k8sClient.Get(ctx, types.NamespacedName{Name: "volsync-rsync-dest-" + rd.Name, Namespace: rd.Namespace}, job)
+				}, maxWait, interval).Should(Succeed())
+				podSpec := job.Spec.Template.Spec
+				Expect(podSpec.NodeSelector).To(Equal(rd.Spec.Rsync.NodeSelector))
+				Expect(podSpec.Tolerations).To(Equal(rd.Spec.Rsync.Tolerations))
+				Expect(podSpec.Affinity).To(Equal(rd.Spec.Rsync.Affinity))
+			})
+		})
 
 		It("Generates ssh keys automatically", func() {
 			secret := &corev1.Secret{}
@@ -727,6 +727,10 @@ func (r *rsyncSrcReconciler) ensureJob(l logr.Logger) (bool, error) {
 		}
 		r.job.Spec.Template.Spec.Containers[0].Command = []string{"/bin/bash", "-c", "/source.sh"}
 		r.job.Spec.Template.Spec.Containers[0].Image = RsyncContainerImage
+		r.job.Spec.Template.Spec.Containers[0].Resources = corev1.ResourceRequirements{}
+		if r.Instance.Spec.Rsync.MoverResources != nil {
+			r.job.Spec.Template.Spec.Containers[0].Resources = *r.Instance.Spec.Rsync.MoverResources
+		}
 		runAsUser := int64(0)
 		r.job.Spec.Template.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{
 			Capabilities: &corev1.Capabilities{
@@ -743,6 +747,9 @@ func (r *rsyncSrcReconciler) ensureJob(l logr.Logger) (bool, error) {
 		}
 		r.job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
 		r.job.Spec.Template.Spec.ServiceAccountName = r.serviceAccount.Name
+		r.job.Spec.Template.Spec.NodeSelector = r.Instance.Spec.Rsync.NodeSelector
+		r.job.Spec.Template.Spec.Tolerations = r.Instance.Spec.Rsync.Tolerations
+		r.job.Spec.Template.Spec.Affinity = r.Instance.Spec.Rsync.Affinity
 		secretMode := int32(0600)
 		r.job.Spec.Template.Spec.Volumes = []corev1.Volume{
 			{Name: dataVolumeName, VolumeSource: corev1.VolumeSource{
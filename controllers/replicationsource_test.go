@@ -525,6 +525,75 @@ var _ = Describe("ReplicationSource", func() {
 		})
 	})
 
+	Context("rsync: when moverResources are specified", func() {
+		BeforeEach(func() {
+			rs.Spec.Rsync = &volsyncv1alpha1.ReplicationSourceRsyncSpec{
+				ReplicationSourceVolumeOptions: volsyncv1alpha1.ReplicationSourceVolumeOptions{
+					CopyMethod: volsyncv1alpha1.CopyMethodClone,
+				},
+				MoverResources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("100m"),
+						corev1.ResourceMemory: resource.MustParse("64Mi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+			}
+		})
+		It("they are used by the sync Job", func() {
+			job := &batchv1.Job{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: "volsync-rsync-src-" + rs.Name, Namespace: rs.Namespace}, job)
+			}, maxWait, interval).Should(Succeed())
+			resources := job.Spec.Template.Spec.Containers[0].Resources
+			Expect(resources.Requests.Cpu().Equal(resource.MustParse("100m"))).To(BeTrue())
+			Expect(resources.Requests.Memory().Equal(resource.MustParse("64Mi"))).To(BeTrue())
+			Expect(resources.Limits.Memory().Equal(resource.MustParse("1Gi"))).To(BeTrue())
+		})
+	})
+
+	Context("rsync: when scheduling constraints are specified", func() {
+		BeforeEach(func() {
+			rs.Spec.Rsync = &volsyncv1alpha1.ReplicationSourceRsyncSpec{
+				ReplicationSourceVolumeOptions: volsyncv1alpha1.ReplicationSourceVolumeOptions{
+					CopyMethod: volsyncv1alpha1.CopyMethodClone,
+				},
+				NodeSelector: map[string]string{"storage": "fast"},
+				Tolerations: []corev1.Toleration{{
+					Key:      "dedicated",
+					Operator: corev1.TolerationOpEqual,
+					Value:    "storage",
+					Effect:   corev1.TaintEffectNoSchedule,
+				}},
+				Affinity: &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+								MatchExpressions: []corev1.NodeSelectorRequirement{{
+									Key:      "topology.kubernetes.io/zone",
+									Operator: corev1.NodeSelectorOpIn,
+									Values:   []string{"zone-a"},
+								}},
+							}},
+						},
+					},
+				},
+			}
+		})
+		It("they are used by the sync Job", func() {
+			job := &batchv1.Job{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: "volsync-rsync-src-" + rs.Name, Namespace: rs.Namespace}, job)
+			}, maxWait, interval).Should(Succeed())
+			podSpec := job.Spec.Template.Spec
+			Expect(podSpec.NodeSelector).To(Equal(rs.Spec.Rsync.NodeSelector))
+			Expect(podSpec.Tolerations).To(Equal(rs.Spec.Rsync.Tolerations))
+			Expect(podSpec.Affinity).To(Equal(rs.Spec.Rsync.Affinity))
+		})
+	})
+
 	Context("rsync: when no key is provided", func() {
 		BeforeEach(func() {
 			rs.Spec.Rsync = &volsyncv1alpha1.ReplicationSourceRsyncSpec{
@@ -0,0 +1,41 @@
+package endpoint
+
+import (
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ManagedAnnotationsAnnotation records the annotations an endpoint has set on one of its
+// resources, so that the ones which are no longer requested are removed again
+const ManagedAnnotationsAnnotation = "volsync.backube/managed-annotations"
+
+// ReconcileAnnotations sets the requested annotations on obj and removes the ones set by a
+// previous reconcile which are no longer requested, annotations set by others are left
+// untouched
+func ReconcileAnnotations(obj metav1.Object, requested map[string]string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	for _, key := range strings.Split(annotations[ManagedAnnotationsAnnotation], ",") {
+		if _, found := requested[key]; !found {
+			delete(annotations, key)
+		}
+	}
+
+	keys := []string{}
+	for k, v := range requested {
+		annotations[k] = v
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		delete(annotations, ManagedAnnotationsAnnotation)
+	} else {
+		sort.Strings(keys)
+		annotations[ManagedAnnotationsAnnotation] = strings.Join(keys, ",")
+	}
+	obj.SetAnnotations(annotations)
+}
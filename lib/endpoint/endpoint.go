@@ -5,16 +5,30 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Port is a single named port exposed by an Endpoint
+type Port struct {
+	// Name identifies the port, it must be unique within an Endpoint
+	Name string
+	// IngressPort is a port which is used by the clients to connect to the endpoint
+	IngressPort int32
+	// BackendPort is a port which can be used by the application behind this endpoint
+	BackendPort int32
+}
+
 // Endpoint knows how to connect with a Transport or a Transfer
 type Endpoint interface {
 	// NamespacedName returns a ns name to identify this endpoint
 	NamespacedName() types.NamespacedName
 	// Hostname returns a hostname for the endpoint
 	Hostname() string
-	// BackendPort returns a port which can be used by the application behind this endpoint
+	// BackendPort returns a port which can be used by the application behind this endpoint,
+	// it is the backend port of the first entry in Ports()
 	BackendPort() int32
-	// IngressPort is a port which is used by the clients to connect to the endpoint
+	// IngressPort is a port which is used by the clients to connect to the endpoint,
+	// it is the ingress port of the first entry in Ports()
 	IngressPort() int32
+	// Ports returns all the ports exposed by the endpoint
+	Ports() []Port
 	// IsHealthy returns whether or not all Kube resources used by endpoint are healthy
 	IsHealthy(c client.Client) (bool, error)
 }
@@ -0,0 +1,179 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/backube/volsync/lib/endpoint"
+	"github.com/backube/volsync/lib/meta"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	errorsutil "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// NginxSSLPassthroughAnnotation enables TLS passthrough on the ingress-nginx controller,
+	// the controller must be started with --enable-ssl-passthrough
+	NginxSSLPassthroughAnnotation = "nginx.ingress.kubernetes.io/ssl-passthrough"
+)
+
+var IngressPort int32 = 443
+
+type Endpoint struct {
+	hostname         string
+	port             int32
+	ingressClassName *string
+	annotations      map[string]string
+	lookupHost       func(host string) ([]string, error)
+	namespacedName   types.NamespacedName
+	objMeta          meta.ObjectMetaMutation
+}
+
+// NewEndpoint creates an Ingress for the given hostname with TLS passthrough to a ClusterIP
+// Service listening on backendPort. When annotations is nil, the ingress-nginx SSL passthrough
+// annotation is used, other ingress controllers need their own annotations to be passed in.
+func NewEndpoint(c client.Client,
+	namespacedName types.NamespacedName,
+	metaMutation meta.ObjectMetaMutation,
+	hostname string,
+	backendPort int32,
+	ingressClassName *string,
+	annotations map[string]string) (endpoint.Endpoint, error) {
+	if hostname == "" {
+		return nil, fmt.Errorf("hostname is required for ingress endpoint %s", namespacedName)
+	}
+
+	if annotations == nil {
+		annotations = map[string]string{
+			NginxSSLPassthroughAnnotation: "true",
+		}
+	}
+
+	i := &Endpoint{
+		hostname:         hostname,
+		port:             backendPort,
+		ingressClassName: ingressClassName,
+		annotations:      annotations,
+		lookupHost:       net.LookupHost,
+		namespacedName:   namespacedName,
+		objMeta:          metaMutation,
+	}
+
+	errs := []error{}
+
+	err := i.reconcileServiceForIngress(c)
+	errs = append(errs, err)
+
+	err = i.reconcileIngress(c)
+	errs = append(errs, err)
+
+	return i, errorsutil.NewAggregate(errs)
+}
+
+func (i *Endpoint) Hostname() string {
+	return i.hostname
+}
+
+func (i *Endpoint) BackendPort() int32 {
+	return i.port
+}
+
+func (i *Endpoint) NamespacedName() types.NamespacedName {
+	return i.namespacedName
+}
+
+func (i *Endpoint) IngressPort() int32 {
+	return IngressPort
+}
+
+func (i *Endpoint) Ports() []endpoint.Port {
+	return []endpoint.Port{
+		{
+			Name:        i.NamespacedName().Name,
+			IngressPort: i.IngressPort(),
+			BackendPort: i.BackendPort(),
+		},
+	}
+}
+
+// IsHealthy returns true once the ingress controller has assigned an address to the Ingress
+// and the hostname of the endpoint resolves. A hostname that does not resolve yet, e.g.
+// while an external DNS record is being created, is reported as not healthy.
+func (i *Endpoint) IsHealthy(c client.Client) (bool, error) {
+	ingress := &networkingv1.Ingress{}
+	err := c.Get(context.TODO(), i.NamespacedName(), ingress)
+	if err != nil {
+		return false, err
+	}
+
+	if len(ingress.Status.LoadBalancer.Ingress) == 0 {
+		return false, nil
+	}
+
+	if _, err = i.lookupHost(i.Hostname()); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (i *Endpoint) reconcileServiceForIngress(c client.Client) error {
+	_, err := endpoint.ReconcileService(c, i.NamespacedName(), i.objMeta,
+		corev1.ServiceTypeClusterIP, i.Ports(), nil)
+	return err
+}
+
+func (i *Endpoint) reconcileIngress(c client.Client) error {
+	pathType := networkingv1.PathTypePrefix
+
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      i.NamespacedName().Name,
+			Namespace: i.NamespacedName().Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(context.TODO(), c, ingress, func() error {
+		ingress.Spec = networkingv1.IngressSpec{
+			IngressClassName: i.ingressClassName,
+			TLS: []networkingv1.IngressTLS{
+				{
+					Hosts: []string{i.Hostname()},
+				},
+			},
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: i.Hostname(),
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: i.NamespacedName().Name,
+											Port: networkingv1.ServiceBackendPort{
+												Number: i.BackendPort(),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		endpoint.ReconcileAnnotations(ingress, i.annotations)
+		ingress.Labels = i.objMeta.Labels()
+		ingress.OwnerReferences = i.objMeta.OwnerReferences()
+		return nil
+	})
+
+	return err
+}
@@ -0,0 +1,140 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/backube/volsync/lib/endpoint/internal/endpointtest"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewEndpoint(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	name := types.NamespacedName{Namespace: "foo", Name: "bar"}
+	m := endpointtest.NewMetaMutation(t)
+
+	e, err := NewEndpoint(c, name, m, "bar.example.com", 6443, nil, nil)
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+	if e.Hostname() != "bar.example.com" || e.IngressPort() != 443 || e.BackendPort() != 6443 {
+		t.Errorf("unexpected endpoint %s:%d->%d", e.Hostname(), e.IngressPort(), e.BackendPort())
+	}
+
+	svc := &corev1.Service{}
+	if err = c.Get(context.TODO(), name, svc); err != nil {
+		t.Fatalf("unable to get service: %v", err)
+	}
+	if svc.Spec.Type != corev1.ServiceTypeClusterIP || svc.Spec.Ports[0].Port != 6443 {
+		t.Errorf("unexpected service spec %v", svc.Spec)
+	}
+
+	ingress := &networkingv1.Ingress{}
+	if err = c.Get(context.TODO(), name, ingress); err != nil {
+		t.Fatalf("unable to get ingress: %v", err)
+	}
+	if ingress.Annotations[NginxSSLPassthroughAnnotation] != "true" {
+		t.Errorf("expected the ssl passthrough annotation, got %v", ingress.Annotations)
+	}
+	backend := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+	if ingress.Spec.Rules[0].Host != "bar.example.com" || backend.Name != "bar" || backend.Port.Number != 6443 {
+		t.Errorf("unexpected ingress rule %v", ingress.Spec.Rules[0])
+	}
+}
+
+func TestNewEndpointUpdatesExistingIngress(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	name := types.NamespacedName{Namespace: "foo", Name: "bar"}
+	m := endpointtest.NewMetaMutation(t)
+
+	_, err := NewEndpoint(c, name, m, "bar.example.com", 6443, nil, nil)
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+
+	// simulate another controller annotating the Ingress
+	ingress := &networkingv1.Ingress{}
+	if err = c.Get(context.TODO(), name, ingress); err != nil {
+		t.Fatalf("unable to get ingress: %v", err)
+	}
+	ingress.Annotations["cert-manager.io/issuer"] = "letsencrypt"
+	if err = c.Update(context.TODO(), ingress); err != nil {
+		t.Fatalf("unable to update ingress: %v", err)
+	}
+
+	// switching away from the default annotations removes them again
+	_, err = NewEndpoint(c, name, m, "bar.example.com", 7443, nil, map[string]string{"example.com/passthrough": "true"})
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+
+	svc := &corev1.Service{}
+	if err = c.Get(context.TODO(), name, svc); err != nil {
+		t.Fatalf("unable to get service: %v", err)
+	}
+	ingress = &networkingv1.Ingress{}
+	if err = c.Get(context.TODO(), name, ingress); err != nil {
+		t.Fatalf("unable to get ingress: %v", err)
+	}
+	backendPort := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number
+	if backendPort != 7443 || svc.Spec.Ports[0].Port != backendPort {
+		t.Errorf("ingress backend port %d does not match the service ports %v", backendPort, svc.Spec.Ports)
+	}
+	if ingress.Annotations["cert-manager.io/issuer"] != "letsencrypt" {
+		t.Errorf("annotations set by other controllers were removed, got %v", ingress.Annotations)
+	}
+	if _, found := ingress.Annotations[NginxSSLPassthroughAnnotation]; found {
+		t.Errorf("expected the ssl passthrough annotation to be removed, got %v", ingress.Annotations)
+	}
+	if ingress.Annotations["example.com/passthrough"] != "true" {
+		t.Errorf("expected the requested annotation, got %v", ingress.Annotations)
+	}
+}
+
+func TestIsHealthy(t *testing.T) {
+	tests := []struct {
+		name    string
+		address bool
+		resolve bool
+		want    bool
+	}{
+		{name: "no address assigned", address: false, resolve: true, want: false},
+		{name: "hostname does not resolve yet", address: true, resolve: false, want: false},
+		{name: "address assigned and hostname resolves", address: true, resolve: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingress := &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"},
+			}
+			if tt.address {
+				ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+			}
+			c := fake.NewClientBuilder().WithObjects(ingress).Build()
+			e := &Endpoint{
+				hostname:       "bar.example.com",
+				namespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"},
+				lookupHost: func(host string) ([]string, error) {
+					if tt.resolve {
+						return []string{"10.0.0.1"}, nil
+					}
+					return nil, fmt.Errorf("no such host %s", host)
+				},
+			}
+
+			got, err := e.IsHealthy(c)
+			if err != nil {
+				t.Fatalf("IsHealthy() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsHealthy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// Package endpointtest provides fixtures shared by the tests of the endpoint packages
+package endpointtest
+
+import (
+	"testing"
+
+	"github.com/backube/volsync/lib/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewMetaMutation returns an ObjectMetaMutation which labels the endpoint resources,
+// and selects the backend Pods, with app=test
+func NewMetaMutation(t *testing.T) meta.ObjectMetaMutation {
+	t.Helper()
+	m, err := meta.NewObjectMetaMutation(&metav1.ObjectMeta{
+		Labels: map[string]string{"app": "test"},
+	}, meta.MutationTypeReplace)
+	if err != nil {
+		t.Fatalf("unable to create meta mutation: %v", err)
+	}
+	return m
+}
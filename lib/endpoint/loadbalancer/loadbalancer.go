@@ -2,22 +2,37 @@ package loadbalancer
 
 import (
 	"context"
+	"fmt"
+
 	"github.com/backube/volsync/lib/endpoint"
 	"github.com/backube/volsync/lib/meta"
+	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// Options configures the cloud-specific parts of the LoadBalancer Service and the logger
+// used to report changes made to it
+type Options struct {
+	// Annotations are added to the Service, e.g. to request an internal load balancer
+	Annotations map[string]string
+	// SourceRanges restricts the client CIDRs allowed to reach the load balancer
+	SourceRanges []string
+	// LoadBalancerIP requests a specific IP for the load balancer
+	LoadBalancerIP string
+	// ExternalTrafficPolicy sets the external traffic policy of the Service, defaults to Cluster
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType
+	// Logger is used to report changes made to the Service, defaults to a discard logger
+	Logger logr.Logger
+}
+
 type Endpoint struct {
 	hostname       string
-	ingressPort    int32
-	backendPort    int32
+	ports          []endpoint.Port
 	namespacedName types.NamespacedName
 	objMeta        meta.ObjectMetaMutation
+	options        Options
 }
 
 func (e *Endpoint) NamespacedName() types.NamespacedName {
@@ -29,11 +44,15 @@ func (e *Endpoint) Hostname() string {
 }
 
 func (e *Endpoint) BackendPort() int32 {
-	return e.backendPort
+	return e.ports[0].BackendPort
 }
 
 func (e *Endpoint) IngressPort() int32 {
-	return e.ingressPort
+	return e.ports[0].IngressPort
+}
+
+func (e *Endpoint) Ports() []endpoint.Port {
+	return e.ports
 }
 
 func (e *Endpoint) IsHealthy(c client.Client) (bool, error) {
@@ -59,11 +78,54 @@ func NewEndpoint(c client.Client,
 	name types.NamespacedName,
 	metaMutation meta.ObjectMetaMutation,
 	backendPort, ingressPort int32) (endpoint.Endpoint, error) {
+	return NewEndpointWithPorts(c, name, metaMutation, []endpoint.Port{
+		{
+			Name:        name.Name,
+			IngressPort: ingressPort,
+			BackendPort: backendPort,
+		},
+	})
+}
+
+// NewEndpointWithPorts creates a LoadBalancer Service exposing all the given ports,
+// the first port is the one reported by IngressPort() and BackendPort()
+func NewEndpointWithPorts(c client.Client,
+	name types.NamespacedName,
+	metaMutation meta.ObjectMetaMutation,
+	ports []endpoint.Port) (endpoint.Endpoint, error) {
+	return NewEndpointWithOptions(c, name, metaMutation, ports, Options{})
+}
+
+// NewEndpointWithOptions is like NewEndpointWithPorts, the options configure the
+// cloud-specific parts of the Service and the logger
+func NewEndpointWithOptions(c client.Client,
+	name types.NamespacedName,
+	metaMutation meta.ObjectMetaMutation,
+	ports []endpoint.Port,
+	options Options) (endpoint.Endpoint, error) {
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("at least one port is required for loadbalancer endpoint %s", name)
+	}
+	names := map[string]bool{}
+	for _, port := range ports {
+		if port.Name == "" {
+			return nil, fmt.Errorf("all ports of loadbalancer endpoint %s must be named", name)
+		}
+		if names[port.Name] {
+			return nil, fmt.Errorf("duplicate port name %s for loadbalancer endpoint %s", port.Name, name)
+		}
+		names[port.Name] = true
+	}
+
+	if options.Logger == nil {
+		options.Logger = logr.Discard()
+	}
+
 	s := &Endpoint{
 		namespacedName: name,
 		objMeta:        metaMutation,
-		backendPort:    backendPort,
-		ingressPort:    ingressPort,
+		ports:          ports,
+		options:        options,
 	}
 
 	err := s.createService(c)
@@ -75,39 +137,21 @@ func NewEndpoint(c client.Client,
 }
 
 func (e *Endpoint) createService(c client.Client) error {
-	serviceSelector := e.objMeta.Labels()
-
-	service := &corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      e.NamespacedName().Name,
-			Namespace: e.NamespacedName().Namespace,
-		},
-	}
-
-	// TODO: log the return operation from CreateOrUpdate
-	_, err := controllerutil.CreateOrUpdate(context.TODO(), c, service, func() error {
-		if service.CreationTimestamp.IsZero() {
-			service.Spec = corev1.ServiceSpec{
-				Ports: []corev1.ServicePort{
-					{
-						Name:     e.NamespacedName().Name,
-						Protocol: corev1.ProtocolTCP,
-						Port:     e.IngressPort(),
-						TargetPort: intstr.IntOrString{
-							Type:   intstr.Int,
-							IntVal: e.BackendPort(),
-						},
-					},
-				},
-				Selector: serviceSelector,
-				Type:     corev1.ServiceTypeLoadBalancer,
+	op, err := endpoint.ReconcileService(c, e.NamespacedName(), e.objMeta,
+		corev1.ServiceTypeLoadBalancer, e.Ports(), func(service *corev1.Service) {
+			service.Spec.LoadBalancerSourceRanges = e.options.SourceRanges
+			service.Spec.LoadBalancerIP = e.options.LoadBalancerIP
+			service.Spec.ExternalTrafficPolicy = e.options.ExternalTrafficPolicy
+			if service.Spec.ExternalTrafficPolicy == "" {
+				service.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
 			}
-		}
-
-		service.Labels = e.objMeta.Labels()
-		service.OwnerReferences = e.objMeta.OwnerReferences()
-		return nil
-	})
+			endpoint.ReconcileAnnotations(service, e.options.Annotations)
+		})
+	if err != nil {
+		return err
+	}
 
-	return err
+	e.options.Logger.V(1).Info("loadbalancer service reconciled",
+		"namespace", e.NamespacedName().Namespace, "name", e.NamespacedName().Name, "operation", op)
+	return nil
 }
@@ -0,0 +1,200 @@
+package loadbalancer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backube/volsync/lib/endpoint"
+	"github.com/backube/volsync/lib/endpoint/internal/endpointtest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewEndpointWithPorts(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	name := types.NamespacedName{Namespace: "foo", Name: "bar"}
+	ports := []endpoint.Port{
+		{Name: "control", IngressPort: 8443, BackendPort: 8080},
+		{Name: "data", IngressPort: 9443, BackendPort: 9090},
+	}
+
+	e, err := NewEndpointWithPorts(c, name, endpointtest.NewMetaMutation(t), ports)
+	if err != nil {
+		t.Fatalf("NewEndpointWithPorts() error = %v", err)
+	}
+	if e.IngressPort() != 8443 || e.BackendPort() != 8080 {
+		t.Errorf("expected first port to be 8443/8080, got %d/%d", e.IngressPort(), e.BackendPort())
+	}
+	if len(e.Ports()) != 2 {
+		t.Errorf("expected 2 ports, got %d", len(e.Ports()))
+	}
+
+	svc := &corev1.Service{}
+	err = c.Get(context.TODO(), name, svc)
+	if err != nil {
+		t.Fatalf("unable to get service: %v", err)
+	}
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		t.Errorf("expected service type %s, got %s", corev1.ServiceTypeLoadBalancer, svc.Spec.Type)
+	}
+	if len(svc.Spec.Ports) != len(ports) {
+		t.Fatalf("expected %d service ports, got %d", len(ports), len(svc.Spec.Ports))
+	}
+	for i, p := range ports {
+		got := svc.Spec.Ports[i]
+		if got.Name != p.Name || got.Port != p.IngressPort || got.TargetPort.IntVal != p.BackendPort {
+			t.Errorf("service port %d = %s:%d->%d, want %s:%d->%d", i,
+				got.Name, got.Port, got.TargetPort.IntVal, p.Name, p.IngressPort, p.BackendPort)
+		}
+	}
+}
+
+func TestNewEndpointWithPortsInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		ports []endpoint.Port
+	}{
+		{name: "no ports"},
+		{name: "unnamed port", ports: []endpoint.Port{
+			{Name: "control", IngressPort: 8443, BackendPort: 8080},
+			{IngressPort: 9443, BackendPort: 9090},
+		}},
+		{name: "duplicate port names", ports: []endpoint.Port{
+			{Name: "data", IngressPort: 8443, BackendPort: 8080},
+			{Name: "data", IngressPort: 9443, BackendPort: 9090},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().Build()
+			name := types.NamespacedName{Namespace: "foo", Name: "bar"}
+
+			_, err := NewEndpointWithPorts(c, name, endpointtest.NewMetaMutation(t), tt.ports)
+			if err == nil {
+				t.Errorf("expected an error for ports %v", tt.ports)
+			}
+		})
+	}
+}
+
+func TestNewEndpointWithOptions(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	name := types.NamespacedName{Namespace: "foo", Name: "bar"}
+	ports := []endpoint.Port{{Name: "bar", IngressPort: 443, BackendPort: 8080}}
+	internalLB := "service.beta.kubernetes.io/aws-load-balancer-internal"
+	proxyProtocol := "service.beta.kubernetes.io/aws-load-balancer-proxy-protocol"
+
+	_, err := NewEndpointWithOptions(c, name, endpointtest.NewMetaMutation(t), ports, Options{
+		Annotations:    map[string]string{internalLB: "true", proxyProtocol: "*"},
+		SourceRanges:   []string{"10.0.0.0/8"},
+		LoadBalancerIP: "1.2.3.4",
+	})
+	if err != nil {
+		t.Fatalf("NewEndpointWithOptions() error = %v", err)
+	}
+
+	svc := &corev1.Service{}
+	if err = c.Get(context.TODO(), name, svc); err != nil {
+		t.Fatalf("unable to get service: %v", err)
+	}
+	if svc.Annotations[internalLB] != "true" || svc.Annotations[proxyProtocol] != "*" {
+		t.Errorf("expected the requested annotations, got %v", svc.Annotations)
+	}
+	if len(svc.Spec.LoadBalancerSourceRanges) != 1 || svc.Spec.LoadBalancerSourceRanges[0] != "10.0.0.0/8" {
+		t.Errorf("unexpected source ranges %v", svc.Spec.LoadBalancerSourceRanges)
+	}
+	if svc.Spec.LoadBalancerIP != "1.2.3.4" {
+		t.Errorf("unexpected load balancer IP %s", svc.Spec.LoadBalancerIP)
+	}
+
+	// annotations set by others must survive the next reconcile
+	svc.Annotations["example.com/owner"] = "someone-else"
+	if err = c.Update(context.TODO(), svc); err != nil {
+		t.Fatalf("unable to update service: %v", err)
+	}
+
+	// options changed after the Service has been created must be applied too
+	_, err = NewEndpointWithOptions(c, name, endpointtest.NewMetaMutation(t), ports, Options{
+		Annotations:           map[string]string{proxyProtocol: "*"},
+		SourceRanges:          []string{"192.168.0.0/16"},
+		ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+	})
+	if err != nil {
+		t.Fatalf("NewEndpointWithOptions() error = %v", err)
+	}
+	svc = &corev1.Service{}
+	if err = c.Get(context.TODO(), name, svc); err != nil {
+		t.Fatalf("unable to get service: %v", err)
+	}
+	if _, found := svc.Annotations[internalLB]; found {
+		t.Errorf("removed annotation %s still set, got %v", internalLB, svc.Annotations)
+	}
+	if svc.Annotations[proxyProtocol] != "*" {
+		t.Errorf("expected annotation %s to be kept, got %v", proxyProtocol, svc.Annotations)
+	}
+	if svc.Annotations["example.com/owner"] != "someone-else" {
+		t.Errorf("annotation set by others was removed, got %v", svc.Annotations)
+	}
+	if len(svc.Spec.LoadBalancerSourceRanges) != 1 || svc.Spec.LoadBalancerSourceRanges[0] != "192.168.0.0/16" {
+		t.Errorf("source ranges not updated, got %v", svc.Spec.LoadBalancerSourceRanges)
+	}
+	if svc.Spec.LoadBalancerIP != "" {
+		t.Errorf("load balancer IP not cleared, got %s", svc.Spec.LoadBalancerIP)
+	}
+	if svc.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyTypeLocal {
+		t.Errorf("external traffic policy not updated, got %s", svc.Spec.ExternalTrafficPolicy)
+	}
+
+	// removing all the annotations from the options removes the bookkeeping too, and an
+	// unset external traffic policy reverts to the default
+	_, err = NewEndpointWithOptions(c, name, endpointtest.NewMetaMutation(t), ports, Options{})
+	if err != nil {
+		t.Fatalf("NewEndpointWithOptions() error = %v", err)
+	}
+	svc = &corev1.Service{}
+	if err = c.Get(context.TODO(), name, svc); err != nil {
+		t.Fatalf("unable to get service: %v", err)
+	}
+	if len(svc.Annotations) != 1 || svc.Annotations["example.com/owner"] != "someone-else" {
+		t.Errorf("expected only the annotation set by others, got %v", svc.Annotations)
+	}
+	if svc.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyTypeCluster {
+		t.Errorf("external traffic policy not reverted to Cluster, got %s", svc.Spec.ExternalTrafficPolicy)
+	}
+}
+
+func TestIsHealthy(t *testing.T) {
+	tests := []struct {
+		name         string
+		ingress      []corev1.LoadBalancerIngress
+		want         bool
+		wantHostname string
+	}{
+		{name: "no ingress yet", want: false},
+		{name: "hostname", ingress: []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}},
+			want: true, wantHostname: "lb.example.com"},
+		{name: "ip", ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+			want: true, wantHostname: "1.2.3.4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"}}
+			svc.Status.LoadBalancer.Ingress = tt.ingress
+			c := fake.NewClientBuilder().WithObjects(svc).Build()
+			e := &Endpoint{namespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"}}
+
+			got, err := e.IsHealthy(c)
+			if err != nil {
+				t.Fatalf("IsHealthy() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsHealthy() = %v, want %v", got, tt.want)
+			}
+			if e.Hostname() != tt.wantHostname {
+				t.Errorf("Hostname() = %s, want %s", e.Hostname(), tt.wantHostname)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package nodeport
+
+import (
+	"context"
+
+	"github.com/backube/volsync/lib/endpoint"
+	"github.com/backube/volsync/lib/meta"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Endpoint struct {
+	hostname       string
+	nodeAddress    string
+	ingressPort    int32
+	backendPort    int32
+	namespacedName types.NamespacedName
+	objMeta        meta.ObjectMetaMutation
+}
+
+// NewEndpoint creates a NodePort Service forwarding to backendPort. nodeAddress is the
+// externally reachable address of the nodes reported by Hostname(), when it is empty
+// the ExternalIP (or InternalIP) of a Ready node is used.
+//
+// IsHealthy lists the cluster's nodes, so the client needs cluster-scoped list
+// permission on nodes. The operator's ClusterRole does not grant it yet.
+func NewEndpoint(c client.Client,
+	name types.NamespacedName,
+	metaMutation meta.ObjectMetaMutation,
+	backendPort int32,
+	nodeAddress string) (endpoint.Endpoint, error) {
+	n := &Endpoint{
+		namespacedName: name,
+		objMeta:        metaMutation,
+		backendPort:    backendPort,
+		nodeAddress:    nodeAddress,
+		hostname:       nodeAddress,
+	}
+
+	err := n.createService(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+func (n *Endpoint) NamespacedName() types.NamespacedName {
+	return n.namespacedName
+}
+
+func (n *Endpoint) Hostname() string {
+	return n.hostname
+}
+
+func (n *Endpoint) BackendPort() int32 {
+	return n.backendPort
+}
+
+// IngressPort returns the nodePort allocated to the Service, it is only known once the
+// endpoint is healthy
+func (n *Endpoint) IngressPort() int32 {
+	return n.ingressPort
+}
+
+func (n *Endpoint) Ports() []endpoint.Port {
+	return []endpoint.Port{
+		{
+			Name:        n.NamespacedName().Name,
+			IngressPort: n.IngressPort(),
+			BackendPort: n.BackendPort(),
+		},
+	}
+}
+
+// IsHealthy returns true once a nodePort has been allocated to the Service and at
+// least one node with a usable address is Ready
+func (n *Endpoint) IsHealthy(c client.Client) (bool, error) {
+	svc := &corev1.Service{}
+	err := c.Get(context.TODO(), n.NamespacedName(), svc)
+	if err != nil {
+		return false, err
+	}
+
+	if len(svc.Spec.Ports) == 0 || svc.Spec.Ports[0].NodePort == 0 {
+		return false, nil
+	}
+	n.ingressPort = svc.Spec.Ports[0].NodePort
+
+	nodes := &corev1.NodeList{}
+	err = c.List(context.TODO(), nodes)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if !isNodeReady(node) {
+			continue
+		}
+		if n.nodeAddress != "" {
+			return true, nil
+		}
+		address := nodeAddress(node)
+		if address != "" {
+			n.hostname = address
+			return true, nil
+		}
+	}
+
+	// no node is ready yet
+	return false, nil
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// nodeAddress returns the ExternalIP of the node, falling back to its InternalIP
+func nodeAddress(node *corev1.Node) string {
+	internalIP := ""
+	for _, addr := range node.Status.Addresses {
+		switch addr.Type {
+		case corev1.NodeExternalIP:
+			return addr.Address
+		case corev1.NodeInternalIP:
+			if internalIP == "" {
+				internalIP = addr.Address
+			}
+		}
+	}
+	return internalIP
+}
+
+func (n *Endpoint) createService(c client.Client) error {
+	_, err := endpoint.ReconcileService(c, n.NamespacedName(), n.objMeta,
+		corev1.ServiceTypeNodePort, n.Ports(), nil)
+	return err
+}
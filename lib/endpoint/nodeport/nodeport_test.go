@@ -0,0 +1,111 @@
+package nodeport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backube/volsync/lib/endpoint/internal/endpointtest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newNode(name string, ready bool, addresses ...corev1.NodeAddress) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			Addresses:  addresses,
+		},
+	}
+}
+
+func TestIsHealthy(t *testing.T) {
+	internalIP := corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.1"}
+	tests := []struct {
+		name         string
+		nodePort     int32
+		nodeAddress  string
+		nodes        []client.Object
+		want         bool
+		wantHostname string
+	}{
+		{
+			name:     "nodePort not allocated",
+			nodePort: 0,
+			nodes:    []client.Object{newNode("n1", true, internalIP)},
+			want:     false,
+		},
+		{
+			name:     "no ready node",
+			nodePort: 30001,
+			nodes:    []client.Object{newNode("n1", false, internalIP)},
+			want:     false,
+		},
+		{
+			name:     "external IP preferred over internal IP",
+			nodePort: 30001,
+			nodes: []client.Object{
+				newNode("n1", false, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "1.2.3.3"}),
+				newNode("n2", true,
+					corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.2"},
+					corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "1.2.3.4"}),
+			},
+			want:         true,
+			wantHostname: "1.2.3.4",
+		},
+		{
+			name:         "configured node address",
+			nodePort:     30001,
+			nodeAddress:  "nodes.example.com",
+			nodes:        []client.Object{newNode("n1", true, internalIP)},
+			want:         true,
+			wantHostname: "nodes.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(tt.nodes...).Build()
+			name := types.NamespacedName{Namespace: "foo", Name: "bar"}
+			m := endpointtest.NewMetaMutation(t)
+
+			e, err := NewEndpoint(c, name, m, 8080, tt.nodeAddress)
+			if err != nil {
+				t.Fatalf("NewEndpoint() error = %v", err)
+			}
+
+			// nodePorts are allocated by the API server, simulate it
+			svc := &corev1.Service{}
+			if err = c.Get(context.TODO(), name, svc); err != nil {
+				t.Fatalf("unable to get service: %v", err)
+			}
+			if svc.Spec.Type != corev1.ServiceTypeNodePort {
+				t.Errorf("expected service type %s, got %s", corev1.ServiceTypeNodePort, svc.Spec.Type)
+			}
+			svc.Spec.Ports[0].NodePort = tt.nodePort
+			if err = c.Update(context.TODO(), svc); err != nil {
+				t.Fatalf("unable to update service: %v", err)
+			}
+
+			got, err := e.IsHealthy(c)
+			if err != nil {
+				t.Fatalf("IsHealthy() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsHealthy() = %v, want %v", got, tt.want)
+			}
+			if got && e.IngressPort() != tt.nodePort {
+				t.Errorf("IngressPort() = %d, want %d", e.IngressPort(), tt.nodePort)
+			}
+			if e.Hostname() != tt.wantHostname {
+				t.Errorf("Hostname() = %s, want %s", e.Hostname(), tt.wantHostname)
+			}
+		})
+	}
+}
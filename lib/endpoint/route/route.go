@@ -92,6 +92,16 @@ func (r *Endpoint) IngressPort() int32 {
 	return IngressPort
 }
 
+func (r *Endpoint) Ports() []endpoint.Port {
+	return []endpoint.Port{
+		{
+			Name:        r.NamespacedName().Name,
+			IngressPort: r.IngressPort(),
+			BackendPort: r.BackendPort(),
+		},
+	}
+}
+
 func (r *Endpoint) IsHealthy(c client.Client) (bool, error) {
 	route := &routev1.Route{}
 	err := c.Get(context.TODO(), r.NamespacedName(), route)
@@ -116,10 +126,21 @@ func (r *Endpoint) IsHealthy(c client.Client) (bool, error) {
 }
 
 func (r *Endpoint) reconcileServiceForRoute(c client.Client) error {
-	port := r.BackendPort()
-
 	serviceSelector := r.objMeta.Labels()
 
+	servicePorts := []corev1.ServicePort{}
+	for _, port := range r.Ports() {
+		servicePorts = append(servicePorts, corev1.ServicePort{
+			Name:     port.Name,
+			Protocol: corev1.ProtocolTCP,
+			Port:     port.BackendPort,
+			TargetPort: intstr.IntOrString{
+				Type:   intstr.Int,
+				IntVal: port.BackendPort,
+			},
+		})
+	}
+
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      r.NamespacedName().Name,
@@ -131,17 +152,7 @@ func (r *Endpoint) reconcileServiceForRoute(c client.Client) error {
 	_, err := controllerutil.CreateOrUpdate(context.TODO(), c, service, func() error {
 		if service.CreationTimestamp.IsZero() {
 			service.Spec = corev1.ServiceSpec{
-				Ports: []corev1.ServicePort{
-					{
-						Name:     r.NamespacedName().Name,
-						Protocol: corev1.ProtocolTCP,
-						Port:     port,
-						TargetPort: intstr.IntOrString{
-							Type:   intstr.Int,
-							IntVal: port,
-						},
-					},
-				},
+				Ports:    servicePorts,
 				Selector: serviceSelector,
 				Type:     corev1.ServiceTypeClusterIP,
 			}
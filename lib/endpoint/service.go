@@ -0,0 +1,80 @@
+package endpoint
+
+import (
+	"context"
+
+	"github.com/backube/volsync/lib/meta"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ReconcileService creates or updates a Service of the given type exposing ports and
+// selecting the Pods labeled with objMeta. The spec is reconciled on every call so that
+// port changes reach an existing Service, allocated nodePorts are kept. mutateFn, when
+// not nil, sets the fields specific to an endpoint type.
+func ReconcileService(c client.Client,
+	name types.NamespacedName,
+	objMeta meta.ObjectMetaMutation,
+	serviceType corev1.ServiceType,
+	ports []Port,
+	mutateFn func(service *corev1.Service)) (controllerutil.OperationResult, error) {
+	servicePorts := []corev1.ServicePort{}
+	for _, port := range ports {
+		servicePorts = append(servicePorts, corev1.ServicePort{
+			Name:     port.Name,
+			Protocol: corev1.ProtocolTCP,
+			Port:     servicePort(serviceType, port),
+			TargetPort: intstr.IntOrString{
+				Type:   intstr.Int,
+				IntVal: port.BackendPort,
+			},
+		})
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name.Name,
+			Namespace: name.Namespace,
+		},
+	}
+
+	return controllerutil.CreateOrUpdate(context.TODO(), c, service, func() error {
+		service.Spec.Ports = preserveNodePorts(service.Spec.Ports, servicePorts)
+		service.Spec.Selector = objMeta.Labels()
+		service.Spec.Type = serviceType
+		if mutateFn != nil {
+			mutateFn(service)
+		}
+
+		service.Labels = objMeta.Labels()
+		service.OwnerReferences = objMeta.OwnerReferences()
+		return nil
+	})
+}
+
+// servicePort returns the port exposed by the Service. Clients connect to LoadBalancer
+// Services directly on the ingress port, other endpoints put a Route, an Ingress or a
+// nodePort in front of the Service which forwards to the backend port.
+func servicePort(serviceType corev1.ServiceType, port Port) int32 {
+	if serviceType == corev1.ServiceTypeLoadBalancer {
+		return port.IngressPort
+	}
+	return port.BackendPort
+}
+
+// preserveNodePorts copies the nodePorts allocated to the existing ports onto the desired
+// ports with the same name, so that updating the Service does not reallocate them
+func preserveNodePorts(existing, desired []corev1.ServicePort) []corev1.ServicePort {
+	for i := range desired {
+		for _, port := range existing {
+			if port.Name == desired[i].Name {
+				desired[i].NodePort = port.NodePort
+			}
+		}
+	}
+	return desired
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/backube/volsync/lib/endpoint"
+	"github.com/backube/volsync/lib/meta"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClusterDomain is the DNS domain of the cluster used to build in-cluster hostnames
+var ClusterDomain = "cluster.local"
+
+// Endpoint is a ClusterIP Service, it is only reachable from within the cluster
+type Endpoint struct {
+	port           int32
+	namespacedName types.NamespacedName
+	objMeta        meta.ObjectMetaMutation
+}
+
+func NewEndpoint(c client.Client,
+	name types.NamespacedName,
+	metaMutation meta.ObjectMetaMutation,
+	port int32) (endpoint.Endpoint, error) {
+	s := &Endpoint{
+		namespacedName: name,
+		objMeta:        metaMutation,
+		port:           port,
+	}
+
+	err := s.createService(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Endpoint) NamespacedName() types.NamespacedName {
+	return s.namespacedName
+}
+
+// Hostname returns the in-cluster DNS name of the Service
+func (s *Endpoint) Hostname() string {
+	return fmt.Sprintf("%s.%s.svc.%s", s.NamespacedName().Name, s.NamespacedName().Namespace, ClusterDomain)
+}
+
+func (s *Endpoint) BackendPort() int32 {
+	return s.port
+}
+
+func (s *Endpoint) IngressPort() int32 {
+	return s.port
+}
+
+func (s *Endpoint) Ports() []endpoint.Port {
+	return []endpoint.Port{
+		{
+			Name:        s.NamespacedName().Name,
+			IngressPort: s.IngressPort(),
+			BackendPort: s.BackendPort(),
+		},
+	}
+}
+
+// IsHealthy returns true once the Service has at least one ready backend address
+func (s *Endpoint) IsHealthy(c client.Client) (bool, error) {
+	endpoints := &corev1.Endpoints{}
+	err := c.Get(context.TODO(), s.NamespacedName(), endpoints)
+	if kerrors.IsNotFound(err) {
+		// the endpoints controller has not created the Endpoints yet
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Endpoint) createService(c client.Client) error {
+	_, err := endpoint.ReconcileService(c, s.NamespacedName(), s.objMeta,
+		corev1.ServiceTypeClusterIP, s.Ports(), nil)
+	return err
+}
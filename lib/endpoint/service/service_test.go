@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/backube/volsync/lib/endpoint/internal/endpointtest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIsHealthy(t *testing.T) {
+	objMeta := metav1.ObjectMeta{Name: "bar", Namespace: "foo"}
+	tests := []struct {
+		name      string
+		endpoints []client.Object
+		want      bool
+		wantErr   bool
+	}{
+		{
+			name: "endpoints not created yet",
+			want: false,
+		},
+		{
+			name: "only not ready addresses",
+			endpoints: []client.Object{&corev1.Endpoints{
+				ObjectMeta: objMeta,
+				Subsets: []corev1.EndpointSubset{
+					{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+				},
+			}},
+			want: false,
+		},
+		{
+			name: "ready address",
+			endpoints: []client.Object{&corev1.Endpoints{
+				ObjectMeta: objMeta,
+				Subsets: []corev1.EndpointSubset{
+					{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+				},
+			}},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(tt.endpoints...).Build()
+			m := endpointtest.NewMetaMutation(t)
+
+			e, err := NewEndpoint(c, types.NamespacedName{Namespace: "foo", Name: "bar"}, m, 8080)
+			if err != nil {
+				t.Fatalf("NewEndpoint() error = %v", err)
+			}
+			if e.Hostname() != "bar.foo.svc.cluster.local" {
+				t.Errorf("Hostname() = %s, want bar.foo.svc.cluster.local", e.Hostname())
+			}
+
+			got, err := e.IsHealthy(c)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsHealthy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IsHealthy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
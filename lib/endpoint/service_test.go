@@ -0,0 +1,88 @@
+package endpoint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backube/volsync/lib/endpoint/internal/endpointtest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestReconcileService(t *testing.T) {
+	tests := []struct {
+		name        string
+		serviceType corev1.ServiceType
+		wantPort    int32
+	}{
+		{name: "clusterip exposes the backend port", serviceType: corev1.ServiceTypeClusterIP, wantPort: 9090},
+		{name: "nodeport exposes the backend port", serviceType: corev1.ServiceTypeNodePort, wantPort: 9090},
+		{name: "loadbalancer exposes the ingress port", serviceType: corev1.ServiceTypeLoadBalancer, wantPort: 443},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().Build()
+			name := types.NamespacedName{Namespace: "foo", Name: "bar"}
+			m := endpointtest.NewMetaMutation(t)
+
+			op, err := ReconcileService(c, name, m, tt.serviceType,
+				[]Port{{Name: "bar", IngressPort: 443, BackendPort: 8080}}, nil)
+			if err != nil {
+				t.Fatalf("ReconcileService() error = %v", err)
+			}
+			if op != controllerutil.OperationResultCreated {
+				t.Errorf("ReconcileService() operation = %s, want %s", op, controllerutil.OperationResultCreated)
+			}
+
+			// simulate the API server populating the Service after creation
+			svc := &corev1.Service{}
+			if err = c.Get(context.TODO(), name, svc); err != nil {
+				t.Fatalf("unable to get service: %v", err)
+			}
+			svc.CreationTimestamp = metav1.Now()
+			svc.Spec.ClusterIP = "172.30.0.10"
+			svc.Spec.Ports[0].NodePort = 30443
+			if err = c.Update(context.TODO(), svc); err != nil {
+				t.Fatalf("unable to update service: %v", err)
+			}
+
+			_, err = ReconcileService(c, name, m, tt.serviceType,
+				[]Port{{Name: "bar", IngressPort: 443, BackendPort: 9090}}, func(service *corev1.Service) {
+					service.Spec.ExternalName = "mutated"
+				})
+			if err != nil {
+				t.Fatalf("ReconcileService() error = %v", err)
+			}
+
+			svc = &corev1.Service{}
+			if err = c.Get(context.TODO(), name, svc); err != nil {
+				t.Fatalf("unable to get service: %v", err)
+			}
+			if svc.Spec.Type != tt.serviceType {
+				t.Errorf("expected service type %s, got %s", tt.serviceType, svc.Spec.Type)
+			}
+			if len(svc.Spec.Ports) != 1 {
+				t.Fatalf("expected 1 service port, got %d", len(svc.Spec.Ports))
+			}
+			got := svc.Spec.Ports[0]
+			if got.Port != tt.wantPort || got.TargetPort.IntVal != 9090 {
+				t.Errorf("service port = %d->%d, want %d->9090", got.Port, got.TargetPort.IntVal, tt.wantPort)
+			}
+			if got.NodePort != 30443 {
+				t.Errorf("node port not preserved, got %d", got.NodePort)
+			}
+			if svc.Spec.ClusterIP != "172.30.0.10" {
+				t.Errorf("cluster IP not preserved, got %s", svc.Spec.ClusterIP)
+			}
+			if svc.Spec.Selector["app"] != "test" || svc.Labels["app"] != "test" {
+				t.Errorf("unexpected selector %v or labels %v", svc.Spec.Selector, svc.Labels)
+			}
+			if svc.Spec.ExternalName != "mutated" {
+				t.Errorf("mutateFn was not applied")
+			}
+		})
+	}
+}